@@ -0,0 +1,86 @@
+package v1
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// CorruptionPolicy controls how a LazyBloomIter reacts when it encounters a
+// bloom page that fails to decode because it's corrupted (bad CRC, malformed
+// header, truncated read).
+type CorruptionPolicy int
+
+const (
+	// FailFast aborts iteration on the first page-decode error, latching it
+	// on Err() unchanged. This is the default and matches historical
+	// behavior: every caller that doesn't opt into SkipCorrupted sees the
+	// exact same error BloomPageDecoder returned.
+	FailFast CorruptionPolicy = iota
+	// SkipCorrupted records the page and advances to the next one instead
+	// of aborting iteration, but only for errors IsCorruptedBloom actually
+	// recognizes as corruption. Everything else - I/O failures reading the
+	// page, ErrPageTooLarge - remains fatal regardless of this policy, since
+	// treating a transient failure as "corrupted and safely skippable" would
+	// silently drop query results.
+	SkipCorrupted
+)
+
+// ErrChecksumMismatch indicates a bloom page's checksum didn't match its
+// decoded contents - a hallmark of on-disk/object-storage corruption rather
+// than a transient I/O failure.
+var ErrChecksumMismatch = errors.New("bloom page checksum mismatch")
+
+// ErrMalformedPageHeader indicates a bloom page's header couldn't be parsed
+// into a sane length/offset, e.g. because the bytes preceding it are
+// corrupted.
+var ErrMalformedPageHeader = errors.New("malformed bloom page header")
+
+// IsCorruptedBloom reports whether err (or one of the errors it wraps)
+// matches a recognized bloom-page corruption signature - a checksum
+// mismatch, a malformed page header, or a truncated read - as opposed to an
+// operational failure such as a transient I/O error. Only errors this
+// returns true for are ever treated as skippable under SkipCorrupted.
+func IsCorruptedBloom(err error) bool {
+	return errors.Is(err, ErrChecksumMismatch) ||
+		errors.Is(err, ErrMalformedPageHeader) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// ErrCorruptedPage wraps an error recognized by IsCorruptedBloom,
+// annotating it with the index of the offending page. It's exposed for
+// callers that want to report or log a corrupted page with that context;
+// LazyBloomIter itself records corrupted pages via CorruptedPages() rather
+// than storing this on Err(), so it never reclassifies the errors
+// BloomPageDecoder returns.
+type ErrCorruptedPage struct {
+	cause     error
+	blockPage int
+}
+
+func (e *ErrCorruptedPage) Error() string {
+	return fmt.Sprintf("corrupted bloom page %d: %s", e.blockPage, e.cause)
+}
+
+func (e *ErrCorruptedPage) Unwrap() error {
+	return e.cause
+}
+
+// NewErrCorruptedPage wraps cause, which must satisfy IsCorruptedBloom, as
+// a corrupted-page error for the given page.
+func NewErrCorruptedPage(cause error, page int) *ErrCorruptedPage {
+	return &ErrCorruptedPage{
+		cause:     cause,
+		blockPage: page,
+	}
+}
+
+var corruptedPagesSkipped = promauto.NewCounter(prometheus.CounterOpts{
+	Namespace: "loki",
+	Subsystem: "bloom",
+	Name:      "corrupted_pages_skipped_total",
+	Help:      "Number of bloom pages skipped by LazyBloomIter because they matched a recognized corruption signature and the iterator was configured with the SkipCorrupted policy.",
+})
@@ -0,0 +1,55 @@
+package v1
+
+import (
+	"io"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsCorruptedBloom(t *testing.T) {
+	require.True(t, IsCorruptedBloom(ErrChecksumMismatch))
+	require.True(t, IsCorruptedBloom(errors.Wrap(ErrChecksumMismatch, "decoding page")))
+	require.True(t, IsCorruptedBloom(ErrMalformedPageHeader))
+	require.True(t, IsCorruptedBloom(io.ErrUnexpectedEOF))
+	require.False(t, IsCorruptedBloom(errors.New("connection reset")))
+	require.False(t, IsCorruptedBloom(ErrPageTooLarge))
+}
+
+func TestHandleDecodeErr_FailFastAbortsOnAnyError(t *testing.T) {
+	it := &LazyBloomIter{corruptionPolicy: FailFast}
+
+	cause := ErrChecksumMismatch
+	cont := it.handleDecodeErr(cause, 2)
+	require.False(t, cont)
+	require.Equal(t, cause, it.Err(), "FailFast must surface the raw decode error unchanged, even for a recognized corruption signature")
+}
+
+func TestHandleDecodeErr_PageTooLargeIsAlwaysFatal(t *testing.T) {
+	it := &LazyBloomIter{corruptionPolicy: SkipCorrupted}
+
+	cont := it.handleDecodeErr(ErrPageTooLarge, 2)
+	require.False(t, cont)
+	require.ErrorIs(t, it.Err(), ErrPageTooLarge)
+}
+
+func TestHandleDecodeErr_NonCorruptionErrorIsFatalUnderSkipCorrupted(t *testing.T) {
+	it := &LazyBloomIter{corruptionPolicy: SkipCorrupted}
+
+	cause := errors.New("getting blooms reader: connection reset")
+	cont := it.handleDecodeErr(cause, 2)
+	require.False(t, cont, "a non-corruption error must remain fatal even under SkipCorrupted")
+	require.Equal(t, cause, it.Err())
+}
+
+func TestHandleDecodeErr_SkipCorruptedAdvancesPastBadPage(t *testing.T) {
+	it := &LazyBloomIter{corruptionPolicy: SkipCorrupted, curPageIndex: 4}
+
+	cont := it.handleDecodeErr(io.ErrUnexpectedEOF, 4)
+	require.True(t, cont)
+	require.NoError(t, it.Err())
+	require.Nil(t, it.curPage)
+	require.Equal(t, 5, it.curPageIndex)
+	require.Equal(t, []BloomOffset{{Page: 4}}, it.CorruptedPages())
+}
@@ -0,0 +1,166 @@
+package v1
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/shirou/gopsutil/v3/mem"
+)
+
+// defaultPoolMemFraction is the fraction of available system memory a
+// BloomPagePool will allow decoded bloom pages to occupy by default.
+const defaultPoolMemFraction = 0.25
+
+// BloomPagePool bounds how many bytes of decoded bloom pages may be
+// resident across all LazyBloomIters that share it. Callers reserve bytes
+// with Acquire/TryAcquire before decoding a page and give them back with
+// Release once the page is no longer needed (LazyBloomIter does this from
+// Relinquish), blocking under Acquire - or failing fast under TryAcquire -
+// once the budget is exhausted.
+type BloomPagePool struct {
+	mu       sync.Mutex
+	cond     *sync.Cond
+	maxBytes int64
+	used     int64
+	waiters  int
+
+	inUseBytes     prometheus.Gauge
+	waitersGauge   prometheus.Gauge
+	released       prometheus.Counter
+	acquireBlocked prometheus.Counter
+}
+
+// NewBloomPagePool returns a BloomPagePool budgeted to fraction of the
+// system's currently available memory, as reported by mem.VirtualMemory().
+// If available memory can't be determined, or fraction of it comes to zero
+// bytes (e.g. fraction is 0), fallbackMaxBytes is used as the budget instead.
+func NewBloomPagePool(reg prometheus.Registerer, fraction float64, fallbackMaxBytes int64) *BloomPagePool {
+	budget := fallbackMaxBytes
+	if vm, err := mem.VirtualMemory(); err == nil {
+		if scaled := int64(float64(vm.Available) * fraction); scaled > 0 {
+			budget = scaled
+		}
+	}
+
+	p := &BloomPagePool{
+		maxBytes: budget,
+		inUseBytes: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Namespace: "loki",
+			Subsystem: "bloom",
+			Name:      "page_pool_in_use_bytes",
+			Help:      "Bytes of decoded bloom pages currently checked out of the shared page pool.",
+		}),
+		waitersGauge: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Namespace: "loki",
+			Subsystem: "bloom",
+			Name:      "page_pool_waiters",
+			Help:      "Number of goroutines currently blocked waiting for room in the shared bloom page pool.",
+		}),
+		released: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Namespace: "loki",
+			Subsystem: "bloom",
+			Name:      "page_pool_releases_total",
+			Help:      "Number of times bytes were released back to the shared bloom page pool.",
+		}),
+		acquireBlocked: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Namespace: "loki",
+			Subsystem: "bloom",
+			Name:      "page_pool_acquire_blocked_total",
+			Help:      "Number of Acquire calls that had to wait for budget to free up, a signal of memory pressure on the shared bloom page pool.",
+		}),
+	}
+	p.cond = sync.NewCond(&p.mu)
+	return p
+}
+
+// NewBloomPagePoolWithDefaultFraction returns a BloomPagePool budgeted to
+// defaultPoolMemFraction of available system memory.
+func NewBloomPagePoolWithDefaultFraction(reg prometheus.Registerer, fallbackMaxBytes int64) *BloomPagePool {
+	return NewBloomPagePool(reg, defaultPoolMemFraction, fallbackMaxBytes)
+}
+
+// TryAcquire reserves n bytes without blocking, returning false if doing so
+// would exceed the pool's budget.
+func (p *BloomPagePool) TryAcquire(n int64) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.used+n > p.maxBytes {
+		return false
+	}
+	p.used += n
+	p.inUseBytes.Set(float64(p.used))
+	return true
+}
+
+// Acquire reserves n bytes, blocking until room is available or ctx is
+// cancelled. It fails immediately, rather than blocking forever, if n alone
+// can never fit within the pool's budget.
+func (p *BloomPagePool) Acquire(ctx context.Context, n int64) error {
+	if n > p.maxBytes {
+		return errors.Errorf("requested %d bytes exceeds page pool budget of %d bytes", n, p.maxBytes)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// sync.Cond has no way to wait on a context directly, so a watcher
+	// goroutine wakes the waiter once ctx is cancelled.
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			p.mu.Lock()
+			p.cond.Broadcast()
+			p.mu.Unlock()
+		case <-done:
+		}
+	}()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.waiters++
+	p.waitersGauge.Set(float64(p.waiters))
+	defer func() {
+		p.waiters--
+		p.waitersGauge.Set(float64(p.waiters))
+	}()
+
+	if p.used+n > p.maxBytes {
+		p.acquireBlocked.Inc()
+	}
+	for p.used+n > p.maxBytes {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		p.cond.Wait()
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	p.used += n
+	p.inUseBytes.Set(float64(p.used))
+	return nil
+}
+
+// Release returns n bytes to the pool and wakes any waiters.
+func (p *BloomPagePool) Release(n int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.used -= n
+	if p.used < 0 {
+		p.used = 0
+	}
+	p.inUseBytes.Set(float64(p.used))
+	p.released.Inc()
+	p.cond.Broadcast()
+}
@@ -0,0 +1,79 @@
+package v1
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBloomPagePool_TryAcquire(t *testing.T) {
+	p := NewBloomPagePool(prometheus.NewRegistry(), 0, 100)
+
+	require.True(t, p.TryAcquire(60))
+	require.True(t, p.TryAcquire(40))
+	require.False(t, p.TryAcquire(1), "budget is fully used")
+
+	p.Release(40)
+	require.True(t, p.TryAcquire(40))
+}
+
+func TestBloomPagePool_AcquireBlocksUntilRelease(t *testing.T) {
+	p := NewBloomPagePool(prometheus.NewRegistry(), 0, 10)
+
+	require.NoError(t, p.Acquire(context.Background(), 10))
+
+	acquired := make(chan error, 1)
+	go func() {
+		acquired <- p.Acquire(context.Background(), 10)
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("Acquire should not have succeeded before Release")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	p.Release(10)
+
+	select {
+	case err := <-acquired:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Acquire did not unblock after Release")
+	}
+}
+
+func TestBloomPagePool_AcquireRespectsContextCancellation(t *testing.T) {
+	p := NewBloomPagePool(prometheus.NewRegistry(), 0, 10)
+	require.NoError(t, p.Acquire(context.Background(), 10))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := p.Acquire(ctx, 10)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestBloomPagePool_AcquireRejectsOversizedRequest(t *testing.T) {
+	p := NewBloomPagePool(prometheus.NewRegistry(), 0, 10)
+
+	err := p.Acquire(context.Background(), 11)
+	require.Error(t, err)
+}
+
+func TestBloomPagePool_AcquireBlockedCountsOnlyContendedAcquires(t *testing.T) {
+	p := NewBloomPagePool(prometheus.NewRegistry(), 0, 10)
+
+	require.NoError(t, p.Acquire(context.Background(), 5))
+	require.Equal(t, float64(0), testutil.ToFloat64(p.acquireBlocked))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := p.Acquire(ctx, 10)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Equal(t, float64(1), testutil.ToFloat64(p.acquireBlocked), "an Acquire that had to wait for budget must be counted")
+}
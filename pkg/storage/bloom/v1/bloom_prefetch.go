@@ -0,0 +1,189 @@
+package v1
+
+import "context"
+
+// prefetchedPage is a page decoded ahead of time by the LazyBloomIter's
+// read-ahead worker, or the error encountered while trying to decode it.
+//
+// err alone doesn't tell the consumer how to treat the failure: fatal
+// distinguishes an operational failure (I/O getting a blooms reader,
+// failing to acquire page-pool budget) from a page-decode error, which is
+// classified by handleDecodeErr the same way the synchronous path is, so
+// SkipCorrupted applies identically regardless of whether the page came
+// from the prefetcher or was decoded inline.
+type prefetchedPage struct {
+	pageIndex int
+	decoder   *BloomPageDecoder
+	err       error
+	fatal     bool
+}
+
+// ensurePrefetch starts the read-ahead worker from page index `from` if
+// prefetching is enabled and no worker is already running. The worker
+// keeps decoding sequentially for as long as the channel (capacity
+// it.prefetch) has room, so once started it doesn't need restarting on
+// every subsequent page advance.
+func (it *LazyBloomIter) ensurePrefetch(from int) {
+	if it.prefetch <= 0 || it.prefetchCh != nil {
+		return
+	}
+	if from >= len(it.b.blooms.pageHeaders) {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan prefetchedPage, it.prefetch)
+
+	it.prefetchCancel = cancel
+	it.prefetchCh = ch
+
+	go it.runPrefetch(ctx, ch, from)
+}
+
+// runPrefetch decodes pages sequentially starting at `from`, pushing each
+// onto ch in order; ch's capacity naturally throttles the worker to at
+// most it.prefetch pages ahead of whatever's being consumed. It stops on
+// the first decode error (pushed as the final item), once it runs off the
+// end of the block, or when ctx is cancelled - in which case any page it
+// had already decoded but not yet delivered is relinquished.
+func (it *LazyBloomIter) runPrefetch(ctx context.Context, ch chan prefetchedPage, from int) {
+	defer close(ch)
+
+	total := len(it.b.blooms.pageHeaders)
+	for page := from; page < total; page++ {
+		decoder, err, fatal := it.prefetchOne(ctx, page)
+
+		select {
+		case ch <- prefetchedPage{pageIndex: page, decoder: decoder, err: err, fatal: fatal}:
+		case <-ctx.Done():
+			if decoder != nil {
+				it.releasePrefetchedDecoder(decoder)
+			}
+			return
+		}
+
+		if err != nil {
+			return
+		}
+	}
+}
+
+// prefetchOne decodes a single page, mirroring the classification the
+// synchronous path applies: failures getting a blooms reader or acquiring
+// page-pool budget are operational (fatal) and must never be treated as
+// skippable corruption, while a BloomPageDecoder error is a genuine
+// page-decode failure left for the consumer to classify via
+// handleDecodeErr.
+func (it *LazyBloomIter) prefetchOne(ctx context.Context, page int) (decoder *BloomPageDecoder, err error, fatal bool) {
+	r, err := it.b.reader.Blooms()
+	if err != nil {
+		return nil, err, true
+	}
+
+	if it.pool != nil {
+		if err := it.pool.Acquire(ctx, int64(it.m)); err != nil {
+			return nil, err, true
+		}
+	}
+
+	decoder, err = it.b.blooms.BloomPageDecoder(r, page, it.m, it.b.metrics)
+	if err != nil {
+		if it.pool != nil {
+			it.pool.Release(int64(it.m))
+		}
+		return nil, err, false
+	}
+
+	return decoder, nil, false
+}
+
+// takePrefetched tries to take the next queued prefetched page for idx.
+// It returns ok=false (with a nil error) if there's no worker running, it
+// has been drained, or its next item doesn't match idx - in all of those
+// cases the caller should fall back to a synchronous decode. A non-nil
+// error means ctx was cancelled while waiting.
+func (it *LazyBloomIter) takePrefetched(ctx context.Context, idx int) (prefetchedPage, bool, error) {
+	if it.prefetchCh == nil {
+		return prefetchedPage{}, false, nil
+	}
+
+	select {
+	case pg, ok := <-it.prefetchCh:
+		if !ok {
+			// exhausted or drained by stopPrefetch
+			it.prefetchCh = nil
+			it.prefetchCancel = nil
+			return prefetchedPage{}, false, nil
+		}
+
+		if pg.pageIndex != idx {
+			// out of sequence, e.g. a Seek jumped elsewhere; this
+			// prefetch run is no longer useful
+			if pg.decoder != nil {
+				it.releasePrefetchedDecoder(pg.decoder)
+			}
+			it.stopPrefetch()
+			return prefetchedPage{}, false, nil
+		}
+
+		return pg, true, nil
+	case <-ctx.Done():
+		return prefetchedPage{}, false, ctx.Err()
+	}
+}
+
+// handlePrefetchErr classifies an error surfaced by the prefetch worker for
+// a page the main iterator has just reached. Fatal (operational) errors
+// latch it.err directly, exactly like the synchronous path does for
+// ErrPageTooLarge and I/O failures. Everything else is routed through
+// handleDecodeErr so SkipCorrupted applies the same way it would to a page
+// decoded inline.
+func (it *LazyBloomIter) handlePrefetchErr(pg prefetchedPage) bool {
+	if pg.fatal {
+		it.err = pg.err
+		it.stopPrefetch()
+		return false
+	}
+	return it.handleDecodeErr(pg.err, pg.pageIndex)
+}
+
+// stopPrefetch cancels any running read-ahead worker and relinquishes any
+// pages it had already queued up. It returns as soon as the worker has been
+// signalled to stop, without waiting for it to actually exit: the worker may
+// be blocked inside an uncancellable call (it.b.reader.Blooms(),
+// BloomPageDecoder) that doesn't take a context.Context and so won't notice
+// cancellation until its current I/O completes. Draining prefetchCh
+// inline until it closed used to mean stopPrefetch - and therefore Close,
+// NextCtx and SeekCtx - could hang for as long as that stuck call did.
+// Instead, the drain happens in a background goroutine that outlives this
+// call; its only job is to relinquish whatever the worker still delivers.
+func (it *LazyBloomIter) stopPrefetch() {
+	if it.prefetchCancel == nil {
+		return
+	}
+
+	it.prefetchCancel()
+
+	ch := it.prefetchCh
+	go func() {
+		for pg := range ch {
+			if pg.decoder != nil {
+				it.releasePrefetchedDecoder(pg.decoder)
+			}
+		}
+	}()
+
+	it.prefetchCancel = nil
+	it.prefetchCh = nil
+}
+
+// releasePrefetchedDecoder returns a prefetched-but-unused page's buffer to
+// the sync.Pool (if usePool) and its budget to the shared pool (if set).
+func (it *LazyBloomIter) releasePrefetchedDecoder(d *BloomPageDecoder) {
+	if it.usePool {
+		d.Relinquish()
+	}
+	if it.pool != nil {
+		it.pool.Release(int64(it.m))
+	}
+}
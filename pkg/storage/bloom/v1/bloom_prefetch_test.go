@@ -0,0 +1,90 @@
+package v1
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandlePrefetchErr_FatalBypassesSkipCorrupted(t *testing.T) {
+	it := &LazyBloomIter{corruptionPolicy: SkipCorrupted}
+
+	cont := it.handlePrefetchErr(prefetchedPage{
+		pageIndex: 1,
+		err:       errors.New("getting blooms reader: connection reset"),
+		fatal:     true,
+	})
+
+	require.False(t, cont, "a fatal (I/O) error must never be skipped, even under SkipCorrupted")
+	require.Error(t, it.Err())
+	require.False(t, IsCorruptedBloom(it.Err()), "fatal errors must not be classified as corruption")
+}
+
+func TestHandlePrefetchErr_NonFatalGoesThroughSkipCorrupted(t *testing.T) {
+	it := &LazyBloomIter{corruptionPolicy: SkipCorrupted, curPageIndex: 1}
+
+	cont := it.handlePrefetchErr(prefetchedPage{
+		pageIndex: 1,
+		err:       ErrChecksumMismatch,
+		fatal:     false,
+	})
+
+	require.True(t, cont)
+	require.NoError(t, it.Err())
+	require.Equal(t, []BloomOffset{{Page: 1}}, it.CorruptedPages())
+}
+
+func TestClose_StopsPrefetchAndReleasesBudget(t *testing.T) {
+	pool := NewBloomPagePool(prometheus.NewRegistry(), 0, 100)
+	require.NoError(t, pool.Acquire(context.Background(), 10))
+
+	ch := make(chan prefetchedPage, 1)
+	ch <- prefetchedPage{pageIndex: 0, decoder: &BloomPageDecoder{}}
+	close(ch)
+
+	it := &LazyBloomIter{
+		usePool:        false,
+		pool:           pool,
+		m:              10,
+		prefetchCh:     ch,
+		prefetchCancel: func() {},
+	}
+
+	it.Close()
+
+	require.Nil(t, it.prefetchCh)
+	require.Nil(t, it.prefetchCancel)
+	require.Eventually(t, func() bool {
+		return pool.TryAcquire(100)
+	}, time.Second, time.Millisecond, "Close should have released the queued page's budget back to the pool, even though the drain is asynchronous")
+}
+
+func TestStopPrefetch_DoesNotBlockOnStuckWorker(t *testing.T) {
+	// ch is never closed, simulating a worker wedged inside an
+	// uncancellable I/O call that hasn't returned from runPrefetch yet.
+	ch := make(chan prefetchedPage)
+
+	it := &LazyBloomIter{
+		prefetchCh:     ch,
+		prefetchCancel: func() {},
+	}
+
+	done := make(chan struct{})
+	go func() {
+		it.stopPrefetch()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("stopPrefetch blocked on a worker that hadn't closed its channel yet")
+	}
+
+	require.Nil(t, it.prefetchCh)
+	require.Nil(t, it.prefetchCancel)
+}
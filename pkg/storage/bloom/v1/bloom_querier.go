@@ -1,6 +1,10 @@
 package v1
 
-import "github.com/pkg/errors"
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
 
 type BloomQuerier interface {
 	Seek(BloomOffset) (*Bloom, error)
@@ -12,11 +16,47 @@ type LazyBloomIter struct {
 	b *Block
 	m int // max page size in bytes
 
+	// pool, if non-nil, bounds the bytes this iterator may hold in
+	// decoded pages at once, shared across other iterators using the
+	// same pool. See NewLazyBloomIterWithPool.
+	pool *BloomPagePool
+
+	corruptionPolicy CorruptionPolicy
+
+	// prefetch is the number of pages to speculatively decode ahead of
+	// curPageIndex. Zero disables read-ahead. See WithPrefetch.
+	prefetch       int
+	prefetchCh     chan prefetchedPage
+	prefetchCancel context.CancelFunc
+
 	// state
 	initialized  bool
 	err          error
 	curPageIndex int
 	curPage      *BloomPageDecoder
+	corrupted    []BloomOffset
+}
+
+// LazyBloomIterOption configures a LazyBloomIter at construction time.
+type LazyBloomIterOption func(*LazyBloomIter)
+
+// WithCorruptionPolicy sets how the iterator behaves when it encounters a
+// page that fails to decode due to corruption. The default is FailFast.
+func WithCorruptionPolicy(p CorruptionPolicy) LazyBloomIterOption {
+	return func(it *LazyBloomIter) {
+		it.corruptionPolicy = p
+	}
+}
+
+// WithPrefetch enables read-ahead: as soon as the iterator advances to a
+// new page, it kicks off decoding of up to the next n pages in the
+// background so that subsequent calls to Next/NextCtx don't block on I/O.
+// Prefetched pages respect the same page pool budget (if any) as
+// synchronously decoded ones.
+func WithPrefetch(n int) LazyBloomIterOption {
+	return func(it *LazyBloomIter) {
+		it.prefetch = n
+	}
 }
 
 // NewLazyBloomIter returns a new lazy bloom iterator.
@@ -24,12 +64,34 @@ type LazyBloomIter struct {
 // will be returned to the pool for efficiency.
 // This can only safely be used when the underlying bloom
 // bytes don't escape the decoder.
-func NewLazyBloomIter(b *Block, pool bool, maxSize int) *LazyBloomIter {
-	return &LazyBloomIter{
+func NewLazyBloomIter(b *Block, pool bool, maxSize int, opts ...LazyBloomIterOption) *LazyBloomIter {
+	it := &LazyBloomIter{
 		usePool: pool,
 		b:       b,
 		m:       maxSize,
 	}
+	for _, opt := range opts {
+		opt(it)
+	}
+	return it
+}
+
+// NewLazyBloomIterWithPool returns a lazy bloom iterator whose page
+// allocations are bounded by pool: before decoding a page it reserves
+// maxSize bytes from pool, blocking if the pool's budget is exhausted, and
+// gives them back once the page is relinquished. It always uses pooled
+// page buffers, equivalent to NewLazyBloomIter(b, true, maxSize).
+func NewLazyBloomIterWithPool(b *Block, pool *BloomPagePool, maxSize int, opts ...LazyBloomIterOption) *LazyBloomIter {
+	it := NewLazyBloomIter(b, true, maxSize, opts...)
+	it.pool = pool
+	return it
+}
+
+// CorruptedPages returns the offsets of pages that were skipped due to
+// corruption while this iterator was running under SkipCorrupted. It is
+// only ever populated when WithCorruptionPolicy(SkipCorrupted) is set.
+func (it *LazyBloomIter) CorruptedPages() []BloomOffset {
+	return it.corrupted
 }
 
 func (it *LazyBloomIter) ensureInit() {
@@ -42,7 +104,14 @@ func (it *LazyBloomIter) ensureInit() {
 	}
 }
 
+// Seek is a thin wrapper around SeekCtx using context.Background().
 func (it *LazyBloomIter) Seek(offset BloomOffset) {
+	it.SeekCtx(context.Background(), offset)
+}
+
+// SeekCtx behaves like Seek but aborts (surfacing ctx.Err() via Err()) if
+// ctx is cancelled while a page is being fetched.
+func (it *LazyBloomIter) SeekCtx(ctx context.Context, offset BloomOffset) {
 	it.ensureInit()
 
 	// reset error from any previous seek/next that yield pages too large
@@ -54,79 +123,162 @@ func (it *LazyBloomIter) Seek(offset BloomOffset) {
 	// load the desired page
 	if it.curPageIndex != offset.Page || it.curPage == nil {
 
-		// drop the current page if it exists and
-		// we're using the pool
-		if it.curPage != nil && it.usePool {
-			it.curPage.Relinquish()
-		}
+		// drop the current page if it exists, returning it to the
+		// sync.Pool and/or the shared byte budget as applicable
+		it.releaseCurrentPage()
 
-		r, err := it.b.reader.Blooms()
+		// takePrefetched drops (and stops) a prefetch run that's no
+		// longer useful, e.g. because this seek jumped elsewhere
+		pg, ok, err := it.takePrefetched(ctx, offset.Page)
 		if err != nil {
-			it.err = errors.Wrap(err, "getting blooms reader")
+			it.err = err
+			it.stopPrefetch()
 			return
 		}
-		decoder, err := it.b.blooms.BloomPageDecoder(r, offset.Page, it.m, it.b.metrics)
-		if err != nil {
-			it.err = errors.Wrap(err, "loading bloom page")
-			return
+		if ok {
+			if pg.err != nil {
+				it.err = errors.Wrap(pg.err, "loading bloom page")
+				it.stopPrefetch()
+				return
+			}
+			it.curPageIndex = offset.Page
+			it.curPage = pg.decoder
+		} else {
+			r, err := it.b.reader.Blooms()
+			if err != nil {
+				it.err = errors.Wrap(err, "getting blooms reader")
+				it.stopPrefetch()
+				return
+			}
+
+			if it.pool != nil {
+				if err := it.pool.Acquire(ctx, int64(it.m)); err != nil {
+					it.err = err
+					it.stopPrefetch()
+					return
+				}
+			}
+
+			decoder, err := it.b.blooms.BloomPageDecoder(r, offset.Page, it.m, it.b.metrics)
+			if err != nil {
+				if it.pool != nil {
+					it.pool.Release(int64(it.m))
+				}
+				it.err = errors.Wrap(err, "loading bloom page")
+				it.stopPrefetch()
+				return
+			}
+
+			it.curPageIndex = offset.Page
+			it.curPage = decoder
 		}
 
-		it.curPageIndex = offset.Page
-		it.curPage = decoder
+		// the fetch itself doesn't take ctx, so check for cancellation once
+		// the page has arrived
+		if err := ctx.Err(); err != nil {
+			it.err = err
+			it.stopPrefetch()
+			return
+		}
 
+		it.ensurePrefetch(it.curPageIndex + 1)
 	}
 
 	it.curPage.Seek(offset.ByteOffset)
 }
 
+// Next is a thin wrapper around NextCtx using context.Background().
 func (it *LazyBloomIter) Next() bool {
+	return it.NextCtx(context.Background())
+}
+
+// NextCtx behaves like Next but aborts (surfacing ctx.Err() via Err()) if
+// ctx is cancelled while a page is being fetched.
+func (it *LazyBloomIter) NextCtx(ctx context.Context) bool {
 	it.ensureInit()
 	if it.err != nil {
 		return false
 	}
-	return it.next()
+	return it.next(ctx)
 }
 
-func (it *LazyBloomIter) next() bool {
+func (it *LazyBloomIter) next(ctx context.Context) bool {
 	if it.err != nil {
 		return false
 	}
 
 	for it.curPageIndex < len(it.b.blooms.pageHeaders) {
+		if err := ctx.Err(); err != nil {
+			it.err = err
+			it.stopPrefetch()
+			return false
+		}
+
 		// first access of next page
 		if it.curPage == nil {
+			pg, ok, err := it.takePrefetched(ctx, it.curPageIndex)
+			if err != nil {
+				it.err = err
+				it.stopPrefetch()
+				return false
+			}
+			if ok {
+				if pg.err != nil {
+					if it.handlePrefetchErr(pg) {
+						continue
+					}
+					return false
+				}
+				it.curPage = pg.decoder
+				it.ensurePrefetch(it.curPageIndex + 1)
+				continue
+			}
+
 			r, err := it.b.reader.Blooms()
 			if err != nil {
 				it.err = errors.Wrap(err, "getting blooms reader")
+				it.stopPrefetch()
 				return false
 			}
 
-			it.curPage, err = it.b.blooms.BloomPageDecoder(
+			if it.pool != nil {
+				if err := it.pool.Acquire(ctx, int64(it.m)); err != nil {
+					it.err = err
+					it.stopPrefetch()
+					return false
+				}
+			}
+
+			decoder, err := it.b.blooms.BloomPageDecoder(
 				r,
 				it.curPageIndex,
 				it.m,
 				it.b.metrics,
 			)
 			if err != nil {
-				it.err = err
+				if it.pool != nil {
+					it.pool.Release(int64(it.m))
+				}
+				if it.handleDecodeErr(err, it.curPageIndex) {
+					continue
+				}
 				return false
 			}
+			it.curPage = decoder
+			it.ensurePrefetch(it.curPageIndex + 1)
 			continue
 		}
 
 		if !it.curPage.Next() {
 			// there was an error
 			if it.curPage.Err() != nil {
+				it.stopPrefetch()
 				return false
 			}
 
 			// we've exhausted the current page, progress to next
 			it.curPageIndex++
-			// drop the current page if it exists and
-			// we're using the pool
-			if it.usePool {
-				it.curPage.Relinquish()
-			}
+			it.releaseCurrentPage()
 			it.curPage = nil
 			continue
 		}
@@ -138,6 +290,56 @@ func (it *LazyBloomIter) next() bool {
 	return false
 }
 
+// handleDecodeErr classifies a page-decode error using IsCorruptedBloom.
+// Only errors it recognizes as a genuine corruption signature are ever
+// eligible to be skipped, and only under SkipCorrupted - everything else
+// (ErrPageTooLarge, I/O failures, anything else BloomPageDecoder returns)
+// latches it.err unchanged and returns false, exactly as it would under the
+// default FailFast policy. When a page is skipped, it's recorded,
+// curPageIndex is advanced, and true is returned so the caller can continue
+// on to the next page.
+func (it *LazyBloomIter) handleDecodeErr(err error, pageIndex int) bool {
+	if it.corruptionPolicy != SkipCorrupted || !IsCorruptedBloom(err) {
+		it.err = err
+		it.stopPrefetch()
+		return false
+	}
+
+	corruptedPagesSkipped.Inc()
+	it.corrupted = append(it.corrupted, BloomOffset{Page: pageIndex})
+
+	it.curPage = nil
+	it.curPageIndex++
+	return true
+}
+
+// releaseCurrentPage returns the current page's byte slice to the
+// sync.Pool (if usePool) and its budget to the shared pool (if set). It is
+// a no-op if there's no current page.
+func (it *LazyBloomIter) releaseCurrentPage() {
+	if it.curPage == nil {
+		return
+	}
+	if it.usePool {
+		it.curPage.Relinquish()
+	}
+	if it.pool != nil {
+		it.pool.Release(int64(it.m))
+	}
+}
+
+// Close tears down the iterator's background prefetch worker, if any, and
+// releases the current page. Callers using WithPrefetch that may abandon
+// iteration before exhausting all pages (e.g. stopping as soon as a match
+// is found, the common case for queriers) must call Close when done -
+// otherwise the prefetch worker and the page-pool budget it holds are
+// never released.
+func (it *LazyBloomIter) Close() {
+	it.stopPrefetch()
+	it.releaseCurrentPage()
+	it.curPage = nil
+}
+
 func (it *LazyBloomIter) At() *Bloom {
 	return it.curPage.At()
 }